@@ -8,7 +8,8 @@ import (
 
 // App struct holds the application state
 type App struct {
-	ctx context.Context
+	ctx       context.Context
+	lexerSpec *parser.LexerSpec
 }
 
 // NewApp creates a new App application struct
@@ -21,9 +22,33 @@ func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
 }
 
+// SetLexerSpec installs a custom set of token rules used by subsequent
+// ParseString/ParseStepByStep/ParseLL1/ParseSLR1/GetTokens calls that don't
+// supply their own lexerSpec override. Pass an empty slice to clear it.
+func (a *App) SetLexerSpec(rules []parser.TokenRule) {
+	if len(rules) == 0 {
+		a.lexerSpec = nil
+		return
+	}
+	a.lexerSpec = &parser.LexerSpec{Rules: rules}
+}
+
+// resolveLexerSpec picks the lexer rules for a call: an explicit
+// per-call override, then the spec installed via SetLexerSpec, then
+// rules auto-derived from the grammar's terminals.
+func (a *App) resolveLexerSpec(grammar *parser.Grammar, override []parser.TokenRule) *parser.LexerSpec {
+	if len(override) > 0 {
+		return &parser.LexerSpec{Rules: override}
+	}
+	if a.lexerSpec != nil {
+		return a.lexerSpec
+	}
+	return parser.DeriveLexerSpec(grammar)
+}
+
 // ParseString parses an input string using the provided grammar
 // and returns the complete parse tree
-func (a *App) ParseString(grammarText string, input string) *parser.ParseResult {
+func (a *App) ParseString(grammarText string, input string, lexerSpec []parser.TokenRule) *parser.ParseResult {
 	// Parse the grammar
 	grammar, err := parser.ParseGrammar(grammarText)
 	if err != nil {
@@ -32,6 +57,7 @@ func (a *App) ParseString(grammarText string, input string) *parser.ParseResult
 			Error:   "Failed to parse grammar: " + err.Error(),
 		}
 	}
+	grammar = grammar.Desugar()
 
 	// Validate the grammar
 	validation := parser.ValidateGrammar(grammar)
@@ -51,11 +77,11 @@ func (a *App) ParseString(grammarText string, input string) *parser.ParseResult
 
 	// Parse the input
 	p := parser.NewParser(grammar)
-	return p.Parse(input, false)
+	return p.Parse(input, false, a.resolveLexerSpec(grammar, lexerSpec))
 }
 
 // ParseStepByStep parses an input string and returns steps for animation
-func (a *App) ParseStepByStep(grammarText string, input string) *parser.ParseResult {
+func (a *App) ParseStepByStep(grammarText string, input string, lexerSpec []parser.TokenRule) *parser.ParseResult {
 	// Parse the grammar
 	grammar, err := parser.ParseGrammar(grammarText)
 	if err != nil {
@@ -64,6 +90,7 @@ func (a *App) ParseStepByStep(grammarText string, input string) *parser.ParseRes
 			Error:   "Failed to parse grammar: " + err.Error(),
 		}
 	}
+	grammar = grammar.Desugar()
 
 	// Validate the grammar
 	validation := parser.ValidateGrammar(grammar)
@@ -83,7 +110,67 @@ func (a *App) ParseStepByStep(grammarText string, input string) *parser.ParseRes
 
 	// Parse the input with step recording
 	p := parser.NewParser(grammar)
-	return p.Parse(input, true)
+	return p.Parse(input, true, a.resolveLexerSpec(grammar, lexerSpec))
+}
+
+// ParseLL1 parses an input string using the table-driven LL(1) parser
+// and returns the resulting parse tree along with any FIRST/FOLLOW
+// conflicts found while building the table
+func (a *App) ParseLL1(grammarText string, input string, lexerSpec []parser.TokenRule) *parser.ParseResult {
+	// Parse the grammar
+	grammar, err := parser.ParseGrammar(grammarText)
+	if err != nil {
+		return &parser.ParseResult{
+			Success: false,
+			Error:   "Failed to parse grammar: " + err.Error(),
+		}
+	}
+	grammar = grammar.Desugar()
+
+	// Parse the input
+	p := parser.NewParser(grammar)
+	return p.ParseLL1(input, true, a.resolveLexerSpec(grammar, lexerSpec))
+}
+
+// ParseSLR1 parses an input string using the shift/reduce SLR(1) parser
+// and returns the resulting parse tree along with the LR(0) automaton
+// and any shift/reduce or reduce/reduce conflicts found while building
+// the ACTION/GOTO tables
+func (a *App) ParseSLR1(grammarText string, input string, lexerSpec []parser.TokenRule) *parser.ParseResult {
+	// Parse the grammar
+	grammar, err := parser.ParseGrammar(grammarText)
+	if err != nil {
+		return &parser.ParseResult{
+			Success: false,
+			Error:   "Failed to parse grammar: " + err.Error(),
+		}
+	}
+	grammar = grammar.Desugar()
+
+	// Parse the input
+	p := parser.NewParser(grammar)
+	return p.ParseSLR1(input, true, a.resolveLexerSpec(grammar, lexerSpec))
+}
+
+// TransformGrammar applies the requested grammar rewrites (left-recursion
+// elimination and/or left-factoring) so the UI can offer a "make LL(1)"
+// button, returning the rewritten grammar's source text alongside the
+// rewritten Grammar (whose TransformSteps describes each rewrite applied)
+func (a *App) TransformGrammar(text string, opts parser.TransformOptions) (string, *parser.Grammar) {
+	grammar, err := parser.ParseGrammar(text)
+	if err != nil {
+		return "", nil
+	}
+	grammar = grammar.Desugar()
+
+	if opts.EliminateLeftRecursion {
+		grammar = parser.EliminateLeftRecursion(grammar)
+	}
+	if opts.LeftFactor {
+		grammar = parser.LeftFactor(grammar)
+	}
+
+	return parser.GrammarToString(grammar), grammar
 }
 
 // ValidateGrammar validates a grammar definition
@@ -96,7 +183,7 @@ func (a *App) ValidateGrammar(grammarText string) *parser.ValidationResult {
 		}
 	}
 
-	return parser.ValidateGrammar(grammar)
+	return parser.ValidateGrammar(grammar.Desugar())
 }
 
 // GetDefaultGrammar returns the default arithmetic expression grammar
@@ -104,8 +191,26 @@ func (a *App) GetDefaultGrammar() string {
 	return parser.GetDefaultArithmeticGrammar()
 }
 
-// GetTokens tokenizes an input string and returns the tokens
-func (a *App) GetTokens(input string) []parser.Token {
-	lexer := parser.NewLexer(input)
+// GetDefaultEBNFGrammar returns an example grammar that demonstrates the
+// EBNF meta-syntax (groups, options, repetitions, quoted literals)
+func (a *App) GetDefaultEBNFGrammar() string {
+	return parser.GetDefaultEBNFGrammar()
+}
+
+// GetTokens tokenizes an input string and returns the tokens. With no
+// grammar in scope, lexerSpec falls back to the installed SetLexerSpec
+// rules, or DefaultLexerSpec if none were set.
+func (a *App) GetTokens(input string, lexerSpec []parser.TokenRule) []parser.Token {
+	var spec *parser.LexerSpec
+	switch {
+	case len(lexerSpec) > 0:
+		spec = &parser.LexerSpec{Rules: lexerSpec}
+	case a.lexerSpec != nil:
+		spec = a.lexerSpec
+	default:
+		spec = parser.DefaultLexerSpec()
+	}
+
+	lexer := parser.NewLexer(input, spec)
 	return lexer.Tokenize()
 }
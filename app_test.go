@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+
+	"parse-tree-viz/parser"
+)
+
+// Regression test: TransformGrammar's whole purpose is to feed its
+// returned string back into the parser (that's what the "make LL(1)"
+// button does), so the rewritten grammar - including any fresh
+// prime-named non-terminals like E' it introduces - must round-trip
+// through ParseGrammar and actually parse.
+func TestTransformGrammarRoundTripsThroughParseGrammar(t *testing.T) {
+	a := NewApp()
+
+	const leftRecursiveArithmetic = `E -> E + T | T
+T -> T * F | F
+F -> "(" E ")" | number`
+
+	text, grammar := a.TransformGrammar(leftRecursiveArithmetic, parser.TransformOptions{EliminateLeftRecursion: true})
+	if grammar == nil {
+		t.Fatalf("TransformGrammar failed to parse input")
+	}
+
+	reparsed, err := parser.ParseGrammar(text)
+	if err != nil {
+		t.Fatalf("ParseGrammar on TransformGrammar's output: %v", err)
+	}
+
+	if _, conflicts, err := parser.BuildLL1Table(reparsed); err != nil || len(conflicts) != 0 {
+		t.Fatalf("expected a conflict-free LL(1) table after round-tripping, conflicts=%v err=%v", conflicts, err)
+	}
+
+	p := parser.NewParser(reparsed)
+	result := p.ParseLL1("1 + 2 * 3", false, nil)
+	if !result.Success {
+		t.Fatalf("ParseLL1 on round-tripped grammar failed: %s", result.Error)
+	}
+}
@@ -0,0 +1,154 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Desugar lowers every EBNF group "(...)", option "[...]", and repetition
+// "{...}" symbol left in g's productions by ParseGrammar into fresh
+// anonymous non-terminals, returning a new Grammar built entirely out of
+// pure BNF productions. Each affected Production's OriginalForm records
+// its pre-lowering (sugared) form for display.
+func (g *Grammar) Desugar() *Grammar {
+	out := cloneGrammar(g)
+	for head, prod := range g.Productions {
+		out.Productions[head].OriginalForm = prod.OriginalForm
+	}
+
+	grpCount, optCount, repCount := 0, 0, 0
+	queue := grammarOrder(out)
+	processed := make(map[string]bool, len(queue))
+
+	for len(queue) > 0 {
+		head := queue[0]
+		queue = queue[1:]
+		if processed[head] {
+			continue
+		}
+		processed[head] = true
+
+		prod := out.Productions[head]
+		if prod == nil {
+			continue
+		}
+		if prod.OriginalForm == "" {
+			prod.OriginalForm = formatOriginalProduction(head, prod.Body)
+		}
+
+		newBody := make([][]string, 0, len(prod.Body))
+		for _, alt := range prod.Body {
+			newAlt := make([]string, 0, len(alt))
+			for _, sym := range alt {
+				if isSugarSymbol(sym) {
+					newName := lowerSugarSymbol(out, sym, &grpCount, &optCount, &repCount)
+					newAlt = append(newAlt, newName)
+					queue = append(queue, newName)
+				} else {
+					newAlt = append(newAlt, sym)
+				}
+			}
+			newBody = append(newBody, newAlt)
+		}
+		prod.Body = newBody
+	}
+
+	recomputeTerminals(out)
+	return out
+}
+
+// lowerSugarSymbol creates the fresh non-terminal for a single sugared
+// group/option/repetition symbol (e.g. "{ \"+\" T }") and returns its name.
+// The new production's body may itself contain sugared symbols; the
+// caller is responsible for queuing it for further lowering.
+func lowerSugarSymbol(g *Grammar, raw string, grpCount, optCount, repCount *int) string {
+	inner := raw
+	if len(raw) >= 2 {
+		inner = raw[1 : len(raw)-1]
+	}
+	alts := splitOnPipe(parseSymbols(inner))
+
+	switch raw[0] {
+	case '(':
+		*grpCount++
+		name := freshNonTerminal(g, fmt.Sprintf("_grp_%d", *grpCount))
+		g.Productions[name] = &Production{Head: name, Body: alts}
+		g.NonTerminals[name] = true
+		g.Order = append(g.Order, name)
+		return name
+
+	case '[':
+		*optCount++
+		name := freshNonTerminal(g, fmt.Sprintf("_opt_%d", *optCount))
+		body := append([][]string{{"ε"}}, alts...)
+		g.Productions[name] = &Production{Head: name, Body: body}
+		g.NonTerminals[name] = true
+		g.Order = append(g.Order, name)
+		return name
+
+	case '{':
+		*repCount++
+		name := freshNonTerminal(g, fmt.Sprintf("_rep_%d", *repCount))
+		body := [][]string{{"ε"}}
+		for _, alt := range alts {
+			body = append(body, append(append([]string{}, alt...), name))
+		}
+		g.Productions[name] = &Production{Head: name, Body: body}
+		g.NonTerminals[name] = true
+		g.Order = append(g.Order, name)
+		return name
+	}
+
+	return raw
+}
+
+// sugaredFormFor returns the EBNF notation a non-terminal introduced by
+// Desugar() stands in for (e.g. "*" for a _rep_ chain), so the UI can
+// fold it back into a single node. It returns "" for ordinary symbols.
+func sugaredFormFor(label string) string {
+	switch {
+	case strings.HasPrefix(label, "_rep_"):
+		return "*"
+	case strings.HasPrefix(label, "_opt_"):
+		return "?"
+	case strings.HasPrefix(label, "_grp_"):
+		return "(group)"
+	default:
+		return ""
+	}
+}
+
+// splitOnPipe splits a flat symbol list on top-level "|" tokens into
+// alternatives, turning any empty alternative into an explicit ε.
+func splitOnPipe(symbols []string) [][]string {
+	alts := [][]string{}
+	cur := []string{}
+
+	for _, s := range symbols {
+		if s == "|" {
+			alts = append(alts, cur)
+			cur = []string{}
+			continue
+		}
+		cur = append(cur, s)
+	}
+	alts = append(alts, cur)
+
+	for i, alt := range alts {
+		if len(alt) == 0 {
+			alts[i] = []string{"ε"}
+		}
+	}
+
+	return alts
+}
+
+// formatOriginalProduction renders a production's (possibly sugared)
+// body back into BNF-like source text for display as OriginalForm.
+func formatOriginalProduction(head string, body [][]string) string {
+	alts := make([]string, len(body))
+	for i, alt := range body {
+		alts[i] = strings.Join(alt, " ")
+	}
+	return fmt.Sprintf("%s -> %s", head, strings.Join(alts, " | "))
+}
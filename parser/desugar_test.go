@@ -0,0 +1,77 @@
+package parser
+
+import "testing"
+
+// Regression test: Desugar() used to infinite-loop on quoted single-char
+// literals like "(" because isSugarSymbol mistook them for an unlowered
+// EBNF group. GetDefaultArithmeticGrammar is the shipped default grammar
+// every App entry point desugars on every call, so this must terminate.
+func TestDesugarDefaultArithmeticGrammarTerminates(t *testing.T) {
+	grammar, err := ParseGrammar(GetDefaultArithmeticGrammar())
+	if err != nil {
+		t.Fatalf("ParseGrammar: %v", err)
+	}
+
+	out := grammar.Desugar()
+
+	for _, alt := range out.Productions["F"].Body {
+		for _, sym := range alt {
+			if isSugarSymbol(sym) {
+				t.Errorf("F still has an unlowered sugar symbol: %q in %v", sym, alt)
+			}
+		}
+	}
+}
+
+// Regression test: the top-level "|" splitter used to run before any
+// bracket awareness, so alternation nested inside a group/option/
+// repetition (like the "+"|"-" inside GetDefaultEBNFGrammar's repetition)
+// was wrongly split as if it were top-level, corrupting the production
+// and panicking in parseSymbols.
+func TestDesugarDefaultEBNFGrammar(t *testing.T) {
+	grammar, err := ParseGrammar(GetDefaultEBNFGrammar())
+	if err != nil {
+		t.Fatalf("ParseGrammar: %v", err)
+	}
+
+	out := grammar.Desugar()
+
+	for head, prod := range out.Productions {
+		for _, alt := range prod.Body {
+			for _, sym := range alt {
+				if isSugarSymbol(sym) {
+					t.Errorf("%s still has an unlowered sugar symbol: %q in %v", head, sym, alt)
+				}
+			}
+		}
+	}
+
+	if _, _, err := BuildLL1Table(out); err != nil {
+		t.Fatalf("BuildLL1Table on desugared EBNF grammar failed: %v", err)
+	}
+}
+
+// Regression test: alternation nested inside a repetition, desugared and
+// then actually parsed end to end - the exact shape that triggered the
+// "slice bounds out of range" panic in parseSymbols.
+func TestDesugarRepetitionWithAlternationParses(t *testing.T) {
+	grammar, err := ParseGrammar(`Expr -> Term { ( "+" | "-" ) Term }
+Term -> number`)
+	if err != nil {
+		t.Fatalf("ParseGrammar: %v", err)
+	}
+
+	out := grammar.Desugar()
+	p := NewParser(out)
+	result := p.ParseLL1("1 + 2 - 3", false, nil)
+	if !result.Success {
+		t.Fatalf("ParseLL1 failed: %s", result.Error)
+	}
+}
+
+func TestSplitTopLevelAlternativesIgnoresNestedPipes(t *testing.T) {
+	got := splitTopLevelAlternatives(` ( "+" | "-" ) Term | other `)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 top-level alternatives, got %d: %v", len(got), got)
+	}
+}
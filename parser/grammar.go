@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 )
@@ -41,6 +42,9 @@ func ParseGrammar(input string) (*Grammar, error) {
 		}
 
 		// Mark as non-terminal
+		if !grammar.NonTerminals[head] {
+			grammar.Order = append(grammar.Order, head)
+		}
 		grammar.NonTerminals[head] = true
 
 		// Set start symbol (first production)
@@ -49,8 +53,9 @@ func ParseGrammar(input string) (*Grammar, error) {
 			isFirst = false
 		}
 
-		// Split alternatives by |
-		alternatives := strings.Split(parts[1], "|")
+		// Split alternatives by top-level | (one that isn't nested inside
+		// an EBNF group/option/repetition or a quoted literal)
+		alternatives := splitTopLevelAlternatives(parts[1])
 		bodies := [][]string{}
 
 		for _, alt := range alternatives {
@@ -76,10 +81,15 @@ func ParseGrammar(input string) (*Grammar, error) {
 		}
 	}
 
-	// Identify terminals (symbols that are not non-terminals)
+	// Identify terminals (symbols that are not non-terminals). EBNF
+	// groups/options/repetitions are left as sugared "(...)"/"[...]"/"{...}"
+	// symbols here; Grammar.Desugar() lowers them before this matters.
 	for _, prod := range grammar.Productions {
 		for _, alt := range prod.Body {
 			for _, symbol := range alt {
+				if isSugarSymbol(symbol) {
+					continue
+				}
 				if !grammar.NonTerminals[symbol] && symbol != "ε" && symbol != "epsilon" {
 					grammar.Terminals[symbol] = true
 				}
@@ -90,35 +100,184 @@ func ParseGrammar(input string) (*Grammar, error) {
 	return grammar, nil
 }
 
-// parseSymbols parses a production body into individual symbols
+// splitTopLevelAlternatives splits a production body on "|" the same
+// way parseSymbols tokenizes it: brackets are matched via scanBalanced
+// and quoted content is skipped, so a "|" nested inside a group/option/
+// repetition or inside a quoted literal is not mistaken for a top-level
+// alternative separator.
+func splitTopLevelAlternatives(body string) []string {
+	alts := []string{}
+	var cur strings.Builder
+
+	i := 0
+	n := len(body)
+	for i < n {
+		ch := body[i]
+		switch {
+		case ch == '(' || ch == '[' || ch == '{':
+			end := scanBalanced(body, i)
+			cur.WriteString(body[i:end])
+			i = end
+
+		case ch == '"':
+			j := i + 1
+			for j < n && body[j] != '"' {
+				j++
+			}
+			if j < n {
+				j++ // consume closing quote
+			}
+			cur.WriteString(body[i:j])
+			i = j
+
+		case ch == '|':
+			alts = append(alts, cur.String())
+			cur.Reset()
+			i++
+
+		default:
+			cur.WriteByte(ch)
+			i++
+		}
+	}
+	alts = append(alts, cur.String())
+
+	return alts
+}
+
+// parseSymbols parses a production body into individual symbols. Beyond
+// plain whitespace-separated BNF symbols, it understands EBNF meta-syntax:
+// quoted terminal literals ("if") are unquoted in place, and balanced
+// groups - ( ... ), options [ ... ], and repetitions { ... } - are kept
+// as a single sugared symbol (e.g. "{ \"+\" T }") for Grammar.Desugar()
+// to lower later. A bare "'" is never treated as a quote delimiter so it
+// stays available for fresh non-terminal names like E' and T'.
 func parseSymbols(body string) []string {
 	symbols := []string{}
-	
+
 	// Handle special tokens
 	body = strings.ReplaceAll(body, "ε", " ε ")
 	body = strings.ReplaceAll(body, "epsilon", " ε ")
-	
-	// Split by whitespace
-	parts := strings.Fields(body)
-	
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		if part != "" {
-			symbols = append(symbols, part)
+
+	i := 0
+	n := len(body)
+	for i < n {
+		ch := body[i]
+
+		if ch == ' ' || ch == '\t' || ch == '\r' {
+			i++
+			continue
+		}
+
+		if ch == '"' {
+			j := i + 1
+			for j < n && body[j] != '"' {
+				j++
+			}
+			content := body[i+1 : j]
+			if j < n {
+				j++ // consume closing quote
+			}
+			if content != "" {
+				symbols = append(symbols, content)
+			}
+			i = j
+			continue
 		}
+
+		if ch == '(' || ch == '[' || ch == '{' {
+			end := scanBalanced(body, i)
+			symbols = append(symbols, body[i:end])
+			i = end
+			continue
+		}
+
+		j := i
+		for j < n {
+			c := body[j]
+			if c == ' ' || c == '\t' || c == '\r' || c == '"' ||
+				c == '(' || c == '[' || c == '{' {
+				break
+			}
+			j++
+		}
+		if j > i {
+			symbols = append(symbols, body[i:j])
+		}
+		i = j
 	}
-	
+
 	return symbols
 }
 
+// scanBalanced returns the index just past the group/option/repetition
+// that starts at body[i] (one of '(', '[', '{'), matching bracket kinds
+// properly and skipping over quoted content so literal terminals such as
+// "(" don't confuse the nesting count.
+func scanBalanced(body string, i int) int {
+	closers := map[byte]byte{'(': ')', '[': ']', '{': '}'}
+	var stack []byte
+	n := len(body)
+	j := i
+
+	for j < n {
+		ch := body[j]
+		switch {
+		case ch == '(' || ch == '[' || ch == '{':
+			stack = append(stack, closers[ch])
+		case ch == ')' || ch == ']' || ch == '}':
+			if len(stack) > 0 && stack[len(stack)-1] == ch {
+				stack = stack[:len(stack)-1]
+			}
+		case ch == '"':
+			j++
+			for j < n && body[j] != '"' {
+				j++
+			}
+		}
+		j++
+		if len(stack) == 0 {
+			return j
+		}
+	}
+
+	return j
+}
+
+// isSugarSymbol reports whether symbol is an as-yet-unlowered EBNF
+// group, option, or repetition produced by scanBalanced in parseSymbols
+// - recognized by starting and ending with a matching bracket pair, so a
+// quoted single-character literal like "(" (which parseSymbols unquotes
+// to the bare symbol "(") is never mistaken for sugar.
+func isSugarSymbol(symbol string) bool {
+	if len(symbol) < 2 {
+		return false
+	}
+	closers := map[byte]byte{'(': ')', '[': ']', '{': '}'}
+	closer, ok := closers[symbol[0]]
+	return ok && symbol[len(symbol)-1] == closer
+}
+
 // GetDefaultArithmeticGrammar returns the default grammar for arithmetic expressions
-// This is an LL(1) compatible grammar (left-recursion removed)
+// This is an LL(1) compatible grammar (left-recursion removed). Parentheses
+// are quoted so they are treated as literal terminals rather than an EBNF
+// grouping construct.
 func GetDefaultArithmeticGrammar() string {
 	return `E  -> T E'
 E' -> + T E' | - T E' | ε
 T  -> F T'
 T' -> * F T' | / F T' | ε
-F  -> ( E ) | number`
+F  -> "(" E ")" | number`
+}
+
+// GetDefaultEBNFGrammar returns an example grammar demonstrating the
+// EBNF meta-syntax supported by ParseGrammar: quoted terminal literals,
+// optional [ ... ], and zero-or-more { ... } constructs. Call
+// Grammar.Desugar() after ParseGrammar to lower it to pure BNF.
+func GetDefaultEBNFGrammar() string {
+	return `Stmt -> "if" Expr "then" Stmt [ "else" Stmt ]
+Expr -> Term { ( "+" | "-" ) Term }
+Term -> "id" | "num"`
 }
 
 // ValidateGrammar checks if the grammar is valid for LL(1) parsing
@@ -159,12 +318,36 @@ func ValidateGrammar(grammar *Grammar) *ValidationResult {
 	for head, prod := range grammar.Productions {
 		for _, alt := range prod.Body {
 			if len(alt) > 0 && alt[0] == head {
-				result.Warnings = append(result.Warnings, 
+				result.Warnings = append(result.Warnings,
 					"Potential left recursion in production: "+head+" -> "+strings.Join(alt, " "))
 			}
 		}
 	}
 
+	if result.Valid {
+		result.FirstSets = ComputeFirst(grammar)
+		result.FollowSets = ComputeFollow(grammar, result.FirstSets)
+		if _, conflicts, err := BuildLL1Table(grammar); err == nil {
+			result.Conflicts = conflicts
+		}
+	}
+
+	return result
+}
+
+// ValidateGrammarStrict runs ValidateGrammar and additionally fails the
+// grammar if it is not LL(1), turning any FIRST/FIRST or FIRST/FOLLOW
+// conflicts reported in ValidationResult.Conflicts into errors.
+func ValidateGrammarStrict(grammar *Grammar) *ValidationResult {
+	result := ValidateGrammar(grammar)
+
+	for _, c := range result.Conflicts {
+		result.Valid = false
+		result.Errors = append(result.Errors, fmt.Sprintf(
+			"LL(1) conflict in <%s> on terminal '%s': %s vs %s",
+			c.NonTerminal, c.Terminal, strings.Join(c.Production1, " "), strings.Join(c.Production2, " ")))
+	}
+
 	return result
 }
 
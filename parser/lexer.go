@@ -1,142 +1,218 @@
 package parser
 
 import (
+	"regexp"
+	"sort"
 	"strings"
-	"unicode"
 )
 
-// Lexer tokenizes input strings for arithmetic expressions
+// TokenRule describes a single lexical rule: an ordered, named regex
+// matched at the current input position. Skip rules (whitespace,
+// comments) are discarded rather than turned into tokens.
+type TokenRule struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+	Skip    bool   `json:"skip"`
+}
+
+// LexerSpec is an ordered list of TokenRules. Rules are tried in order
+// at each position; the longest match wins, with earlier rules breaking
+// ties (leftmost-longest, rule-order tiebreak).
+type LexerSpec struct {
+	Rules []TokenRule `json:"rules"`
+}
+
+// compiledRule is a TokenRule with its pattern compiled and anchored to
+// the start of the remaining input.
+type compiledRule struct {
+	name string
+	re   *regexp.Regexp
+	skip bool
+}
+
+// Lexer tokenizes input strings according to a LexerSpec
 type Lexer struct {
-	input   string
-	pos     int
-	tokens  []Token
+	input  string
+	pos    int
+	line   int
+	column int
+	tokens []Token
+	rules  []compiledRule
 }
 
-// NewLexer creates a new lexer for the given input
-func NewLexer(input string) *Lexer {
+// NewLexer creates a new lexer for the given input, driven by spec. A
+// nil spec falls back to DefaultLexerSpec (the original hard-coded
+// arithmetic rules).
+func NewLexer(input string, spec *LexerSpec) *Lexer {
+	if spec == nil {
+		spec = DefaultLexerSpec()
+	}
+
+	rules := make([]compiledRule, 0, len(spec.Rules))
+	for _, r := range spec.Rules {
+		re, err := regexp.Compile(`\A(?:` + r.Pattern + `)`)
+		if err != nil {
+			continue
+		}
+		rules = append(rules, compiledRule{name: r.Name, re: re, skip: r.Skip})
+	}
+
 	return &Lexer{
 		input:  input,
 		pos:    0,
+		line:   1,
+		column: 1,
 		tokens: []Token{},
+		rules:  rules,
 	}
 }
 
-// Tokenize converts the input string into a slice of tokens
+// Tokenize converts the input string into a slice of tokens by matching
+// rules leftmost-longest at each position; unmatched input yields a
+// TOKEN_UNKNOWN token for that single character so tokenization can
+// continue and report every problem spot.
 func (l *Lexer) Tokenize() []Token {
 	l.tokens = []Token{}
 	l.pos = 0
+	l.line = 1
+	l.column = 1
 
 	for l.pos < len(l.input) {
-		ch := l.input[l.pos]
-
-		// Skip whitespace
-		if unicode.IsSpace(rune(ch)) {
-			l.pos++
-			continue
-		}
-
-		// Number (integer or decimal)
-		if unicode.IsDigit(rune(ch)) {
-			l.tokens = append(l.tokens, l.readNumber())
-			continue
+		remaining := l.input[l.pos:]
+
+		bestLen := -1
+		var bestRule compiledRule
+		for _, r := range l.rules {
+			loc := r.re.FindStringIndex(remaining)
+			if loc == nil {
+				continue
+			}
+			if loc[1] > bestLen {
+				bestLen = loc[1]
+				bestRule = r
+			}
 		}
 
-		// Identifier (for grammar terminals like 'number', 'id')
-		if unicode.IsLetter(rune(ch)) {
-			l.tokens = append(l.tokens, l.readIdentifier())
+		if bestLen <= 0 {
+			l.tokens = append(l.tokens, Token{
+				Type:     TOKEN_UNKNOWN,
+				Value:    remaining[:1],
+				Position: l.pos,
+				Line:     l.line,
+				Column:   l.column,
+			})
+			l.advance(1)
 			continue
 		}
 
-		// Single character tokens
-		token := Token{Position: l.pos}
-		switch ch {
-		case '+':
-			token.Type = TOKEN_PLUS
-			token.Value = "+"
-		case '-':
-			token.Type = TOKEN_MINUS
-			token.Value = "-"
-		case '*':
-			token.Type = TOKEN_MULT
-			token.Value = "*"
-		case '/':
-			token.Type = TOKEN_DIV
-			token.Value = "/"
-		case '(':
-			token.Type = TOKEN_LPAREN
-			token.Value = "("
-		case ')':
-			token.Type = TOKEN_RPAREN
-			token.Value = ")"
-		default:
-			token.Type = TOKEN_UNKNOWN
-			token.Value = string(ch)
+		if !bestRule.skip {
+			l.tokens = append(l.tokens, Token{
+				Type:     tokenTypeForRuleName(bestRule.name),
+				Value:    remaining[:bestLen],
+				Position: l.pos,
+				Line:     l.line,
+				Column:   l.column,
+			})
 		}
-		l.tokens = append(l.tokens, token)
-		l.pos++
+		l.advance(bestLen)
 	}
 
-	// Add EOF token
 	l.tokens = append(l.tokens, Token{
 		Type:     TOKEN_EOF,
 		Value:    "",
 		Position: l.pos,
+		Line:     l.line,
+		Column:   l.column,
 	})
 
 	return l.tokens
 }
 
-// readNumber reads a number (integer or decimal) from the input
-func (l *Lexer) readNumber() Token {
-	start := l.pos
-	hasDecimal := false
-
-	for l.pos < len(l.input) {
-		ch := l.input[l.pos]
-		if unicode.IsDigit(rune(ch)) {
-			l.pos++
-		} else if ch == '.' && !hasDecimal {
-			hasDecimal = true
-			l.pos++
+// advance moves the lexer forward n bytes, tracking line/column.
+func (l *Lexer) advance(n int) {
+	for i := 0; i < n; i++ {
+		if l.input[l.pos] == '\n' {
+			l.line++
+			l.column = 1
 		} else {
-			break
+			l.column++
 		}
+		l.pos++
 	}
+}
 
-	return Token{
-		Type:     TOKEN_NUMBER,
-		Value:    l.input[start:l.pos],
-		Position: start,
+// tokenTypeForRuleName maps well-known rule names to their historical
+// TokenType constants, so the recursive-descent matcher keeps working
+// unchanged; any other rule name becomes its own TokenType.
+func tokenTypeForRuleName(name string) TokenType {
+	switch name {
+	case "number":
+		return TOKEN_NUMBER
+	case "+":
+		return TOKEN_PLUS
+	case "-":
+		return TOKEN_MINUS
+	case "*":
+		return TOKEN_MULT
+	case "/":
+		return TOKEN_DIV
+	case "(":
+		return TOKEN_LPAREN
+	case ")":
+		return TOKEN_RPAREN
+	case "ident":
+		return TOKEN_IDENT
+	default:
+		return TokenType(name)
 	}
 }
 
-// readIdentifier reads an identifier from the input
-func (l *Lexer) readIdentifier() Token {
-	start := l.pos
+// DefaultLexerSpec returns the lexer rules matching the original
+// hard-coded arithmetic lexer: + - * / ( ), decimal numbers,
+// identifiers, and whitespace skipping.
+func DefaultLexerSpec() *LexerSpec {
+	return &LexerSpec{Rules: []TokenRule{
+		{Name: "skip", Pattern: `[ \t\r\n]+`, Skip: true},
+		{Name: "number", Pattern: `[0-9]+(\.[0-9]+)?`},
+		{Name: "+", Pattern: `\+`},
+		{Name: "-", Pattern: `-`},
+		{Name: "*", Pattern: `\*`},
+		{Name: "/", Pattern: `/`},
+		{Name: "(", Pattern: `\(`},
+		{Name: ")", Pattern: `\)`},
+		{Name: "ident", Pattern: `[A-Za-z_][A-Za-z0-9_']*`},
+	}}
+}
 
-	for l.pos < len(l.input) {
-		ch := l.input[l.pos]
-		if unicode.IsLetter(rune(ch)) || unicode.IsDigit(rune(ch)) || ch == '_' || ch == '\'' {
-			l.pos++
-		} else {
-			break
-		}
+// DeriveLexerSpec builds a LexerSpec from a grammar's terminals: the
+// well-known names "number", "ident"/"id", and "string" map to built-in
+// regexes, and every other terminal (punctuation, quoted keywords, ...)
+// becomes a fixed-string literal rule.
+func DeriveLexerSpec(g *Grammar) *LexerSpec {
+	spec := &LexerSpec{Rules: []TokenRule{
+		{Name: "skip", Pattern: `[ \t\r\n]+`, Skip: true},
+	}}
+
+	terminals := make([]string, 0, len(g.Terminals))
+	for t := range g.Terminals {
+		terminals = append(terminals, t)
 	}
-
-	value := l.input[start:l.pos]
-	
-	// Check for special keywords that map to tokens
-	tokenType := TOKEN_IDENT
-	switch strings.ToLower(value) {
-	case "number":
-		tokenType = TOKEN_NUMBER
+	sort.Strings(terminals)
+
+	for _, t := range terminals {
+		switch strings.ToLower(t) {
+		case "number":
+			spec.Rules = append(spec.Rules, TokenRule{Name: "number", Pattern: `[0-9]+(\.[0-9]+)?`})
+		case "ident", "id", "identifier":
+			spec.Rules = append(spec.Rules, TokenRule{Name: "ident", Pattern: `[A-Za-z_][A-Za-z0-9_]*`})
+		case "string":
+			spec.Rules = append(spec.Rules, TokenRule{Name: "string", Pattern: `"[^"]*"`})
+		default:
+			spec.Rules = append(spec.Rules, TokenRule{Name: t, Pattern: regexp.QuoteMeta(t)})
+		}
 	}
 
-	return Token{
-		Type:     tokenType,
-		Value:    value,
-		Position: start,
-	}
+	return spec
 }
 
 // GetTokenTypeName returns a human-readable name for a token type
@@ -159,7 +235,7 @@ func GetTokenTypeName(t TokenType) string {
 	case TOKEN_EOF:
 		return "EOF"
 	case TOKEN_EPSILON:
-		return "Îµ"
+		return "ε"
 	default:
 		return string(t)
 	}
@@ -0,0 +1,54 @@
+package parser
+
+import "testing"
+
+func TestDefaultLexerSpecTokenizesArithmetic(t *testing.T) {
+	lexer := NewLexer("12 + foo * (3)", DefaultLexerSpec())
+	tokens := lexer.Tokenize()
+
+	want := []TokenType{
+		TOKEN_NUMBER, TOKEN_PLUS, TOKEN_IDENT, TOKEN_MULT,
+		TOKEN_LPAREN, TOKEN_NUMBER, TOKEN_RPAREN, TOKEN_EOF,
+	}
+	if len(tokens) != len(want) {
+		t.Fatalf("got %d tokens, want %d: %+v", len(tokens), len(want), tokens)
+	}
+	for i, tok := range tokens {
+		if tok.Type != want[i] {
+			t.Errorf("token %d: got type %s, want %s", i, tok.Type, want[i])
+		}
+	}
+}
+
+func TestLexerTracksLineAndColumn(t *testing.T) {
+	lexer := NewLexer("1\n22", DefaultLexerSpec())
+	tokens := lexer.Tokenize()
+
+	if tokens[0].Line != 1 || tokens[0].Column != 1 {
+		t.Errorf("first token: got line %d column %d, want 1 1", tokens[0].Line, tokens[0].Column)
+	}
+	if tokens[1].Line != 2 || tokens[1].Column != 1 {
+		t.Errorf("second token: got line %d column %d, want 2 1", tokens[1].Line, tokens[1].Column)
+	}
+}
+
+func TestDeriveLexerSpecMapsWellKnownTerminalNames(t *testing.T) {
+	grammar, err := ParseGrammar(`Stmt -> "if" id "then" id`)
+	if err != nil {
+		t.Fatalf("ParseGrammar: %v", err)
+	}
+
+	spec := DeriveLexerSpec(grammar)
+	lexer := NewLexer("if count then total", spec)
+	tokens := lexer.Tokenize()
+
+	wantValues := []string{"if", "count", "then", "total"}
+	if len(tokens) != len(wantValues)+1 {
+		t.Fatalf("got %d tokens, want %d (+EOF): %+v", len(tokens), len(wantValues)+1, tokens)
+	}
+	for i, want := range wantValues {
+		if tokens[i].Value != want {
+			t.Errorf("token %d: got value %q, want %q", i, tokens[i].Value, want)
+		}
+	}
+}
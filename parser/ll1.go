@@ -0,0 +1,473 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Conflict describes two productions that would occupy the same LL(1)
+// table cell for a given non-terminal/terminal pair, or two actions that
+// would occupy the same SLR(1) ACTION cell for a given state/terminal
+// pair (Production1/Production2 then hold the competing actions).
+type Conflict struct {
+	NonTerminal string   `json:"nonTerminal,omitempty"`
+	Terminal    string   `json:"terminal"`
+	Production1 []string `json:"production1"`
+	Production2 []string `json:"production2"`
+	State       int      `json:"state,omitempty"` // offending item set, for SLR(1) conflicts
+}
+
+// LL1Table is the parsing table produced by BuildLL1Table. Entries maps
+// a non-terminal and a lookahead terminal to the index of the winning
+// production within Grammar.Productions[nonTerminal].Body.
+type LL1Table struct {
+	Grammar *Grammar                  `json:"-"`
+	Entries map[string]map[string]int `json:"entries"`
+}
+
+// ComputeFirst computes the FIRST set of every non-terminal in g by
+// fixed-point iteration. "ε" is used to represent the empty string.
+func ComputeFirst(g *Grammar) map[string]map[string]bool {
+	first := make(map[string]map[string]bool)
+	for nt := range g.NonTerminals {
+		first[nt] = make(map[string]bool)
+	}
+
+	changed := true
+	for changed {
+		changed = false
+		for head, prod := range g.Productions {
+			for _, alt := range prod.Body {
+				for t := range firstOfSequence(g, first, alt) {
+					if !first[head][t] {
+						first[head][t] = true
+						changed = true
+					}
+				}
+			}
+		}
+	}
+
+	return first
+}
+
+// firstOfSequence computes FIRST(X1 X2 ... Xn) given the FIRST sets of
+// the individual non-terminals computed so far.
+func firstOfSequence(g *Grammar, first map[string]map[string]bool, seq []string) map[string]bool {
+	result := make(map[string]bool)
+	nullable := true
+
+	for _, sym := range seq {
+		if sym == "ε" || sym == "epsilon" {
+			continue
+		}
+
+		var symFirst map[string]bool
+		if g.IsNonTerminal(sym) {
+			symFirst = first[sym]
+		} else {
+			symFirst = map[string]bool{sym: true}
+		}
+
+		for t := range symFirst {
+			if t != "ε" {
+				result[t] = true
+			}
+		}
+
+		if !symFirst["ε"] {
+			nullable = false
+			break
+		}
+	}
+
+	if nullable {
+		result["ε"] = true
+	}
+
+	return result
+}
+
+// ComputeFollow computes the FOLLOW set of every non-terminal in g,
+// given its FIRST sets, by fixed-point iteration. "$" represents EOF.
+func ComputeFollow(g *Grammar, first map[string]map[string]bool) map[string]map[string]bool {
+	follow := make(map[string]map[string]bool)
+	for nt := range g.NonTerminals {
+		follow[nt] = make(map[string]bool)
+	}
+	if g.StartSymbol != "" {
+		follow[g.StartSymbol]["$"] = true
+	}
+
+	changed := true
+	for changed {
+		changed = false
+		for _, prod := range g.Productions {
+			for _, alt := range prod.Body {
+				for i, sym := range alt {
+					if !g.IsNonTerminal(sym) {
+						continue
+					}
+
+					beta := alt[i+1:]
+					betaFirst := firstOfSequence(g, first, beta)
+
+					for t := range betaFirst {
+						if t == "ε" {
+							continue
+						}
+						if !follow[sym][t] {
+							follow[sym][t] = true
+							changed = true
+						}
+					}
+
+					if betaFirst["ε"] {
+						for t := range follow[prod.Head] {
+							if !follow[sym][t] {
+								follow[sym][t] = true
+								changed = true
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return follow
+}
+
+// BuildLL1Table builds the LL(1) parsing table for g. Any cell that
+// would receive two productions is reported as a Conflict instead of
+// being silently overwritten; the first production registered for a
+// cell is kept.
+func BuildLL1Table(g *Grammar) (*LL1Table, []Conflict, error) {
+	if g.StartSymbol == "" {
+		return nil, nil, fmt.Errorf("grammar has no start symbol")
+	}
+
+	first := ComputeFirst(g)
+	follow := ComputeFollow(g, first)
+
+	table := &LL1Table{
+		Grammar: g,
+		Entries: make(map[string]map[string]int),
+	}
+	conflicts := []Conflict{}
+
+	for head, prod := range g.Productions {
+		table.Entries[head] = make(map[string]int)
+
+		for idx, alt := range prod.Body {
+			altFirst := firstOfSequence(g, first, alt)
+
+			for t := range altFirst {
+				if t == "ε" {
+					continue
+				}
+				setLL1Entry(table, &conflicts, prod, head, t, idx)
+			}
+
+			if altFirst["ε"] {
+				for t := range follow[head] {
+					setLL1Entry(table, &conflicts, prod, head, t, idx)
+				}
+			}
+		}
+	}
+
+	return table, conflicts, nil
+}
+
+// setLL1Entry records production idx in cell (head, terminal), or
+// reports a Conflict if the cell is already occupied by a different
+// production.
+func setLL1Entry(table *LL1Table, conflicts *[]Conflict, prod *Production, head, terminal string, idx int) {
+	if existing, ok := table.Entries[head][terminal]; ok {
+		if existing != idx {
+			*conflicts = append(*conflicts, Conflict{
+				NonTerminal: head,
+				Terminal:    terminal,
+				Production1: prod.Body[existing],
+				Production2: prod.Body[idx],
+			})
+		}
+		return
+	}
+	table.Entries[head][terminal] = idx
+}
+
+// ll1StackEntry pairs a grammar symbol on the LL(1) parse stack with
+// the tree node it will produce once matched or expanded.
+type ll1StackEntry struct {
+	symbol string
+	node   *TreeNode
+}
+
+// ParseLL1 drives the explicit-stack LL(1) table algorithm over input,
+// recording one Step per stack action when recordSteps is set. A nil
+// lexerSpec falls back to DefaultLexerSpec.
+func (p *Parser) ParseLL1(input string, recordSteps bool, lexerSpec *LexerSpec) *ParseResult {
+	lexer := NewLexer(input, lexerSpec)
+	p.tokens = lexer.Tokenize()
+	p.pos = 0
+	p.nodeID = 0
+	p.steps = []Step{}
+	p.recordSteps = recordSteps
+	p.errors = []ParseError{}
+
+	result := &ParseResult{Success: true, Tokens: p.tokens, Steps: []Step{}}
+
+	if p.grammar.StartSymbol == "" {
+		result.Success = false
+		result.Error = "Grammar has no start symbol"
+		return result
+	}
+
+	first := ComputeFirst(p.grammar)
+	follow := ComputeFollow(p.grammar, first)
+
+	table, conflicts, err := BuildLL1Table(p.grammar)
+	if err != nil {
+		result.Success = false
+		result.Error = err.Error()
+		return result
+	}
+
+	root := p.newTreeNode(p.grammar.StartSymbol, false)
+	stack := []ll1StackEntry{{symbol: "$"}, {symbol: p.grammar.StartSymbol, node: root}}
+
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		tok := p.current()
+		key := terminalKeyForToken(tok)
+
+		if top.symbol == "$" {
+			stack = stack[:len(stack)-1]
+			if tok.Type != TOKEN_EOF {
+				result.Success = false
+				result.Error = fmt.Sprintf("unexpected token '%s' at position %d", tok.Value, tok.Position)
+			}
+			break
+		}
+
+		if top.symbol == "ε" || top.symbol == "epsilon" {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		if !p.grammar.IsNonTerminal(top.symbol) {
+			stack = stack[:len(stack)-1]
+			if !p.tokenMatchesSymbol(tok, top.symbol) {
+				result.Success = false
+				result.Error = fmt.Sprintf("expected '%s', got '%s' at position %d", top.symbol, tok.Value, tok.Position)
+				break
+			}
+			top.node.Label = tok.Value
+			p.advance()
+			if p.recordSteps {
+				p.steps = append(p.steps, Step{
+					Action:         "match",
+					Description:    fmt.Sprintf("Match terminal '%s'", tok.Value),
+					NodeID:         top.node.ID,
+					Stack:          stackSymbols(stack),
+					RemainingInput: p.remainingInput(),
+				})
+			}
+			continue
+		}
+
+		idx, ok := table.Entries[top.symbol][key]
+		if !ok {
+			if p.maxErrorsReached() {
+				result.Success = false
+				result.Error = fmt.Sprintf("no rule for <%s> on input '%s' at position %d", top.symbol, tok.Value, tok.Position)
+				break
+			}
+
+			// Panic-mode recovery: record the diagnostic and mark the
+			// node so the tree still renders with an error marker.
+			p.errors = append(p.errors, ParseError{
+				Message:  fmt.Sprintf("no rule for <%s> on input '%s'", top.symbol, tok.Value),
+				Position: tok.Position,
+				Line:     tok.Line,
+				Column:   tok.Column,
+				Expected: expectedTerminals(table, top.symbol),
+				Found:    tok.Value,
+			})
+			top.node.IsError = true
+			if p.recordSteps {
+				p.steps = append(p.steps, Step{
+					Action:         "error",
+					Description:    fmt.Sprintf("Recovery: no rule for <%s> on '%s'", top.symbol, tok.Value),
+					NodeID:         top.node.ID,
+					Stack:          stackSymbols(stack),
+					RemainingInput: p.remainingInput(),
+				})
+			}
+
+			if follow[top.symbol][key] {
+				// a is in FOLLOW(A): pop A, treating it as an ε-expansion.
+				stack = stack[:len(stack)-1]
+				continue
+			}
+
+			// Otherwise skip input until a token in FIRST(A) ∪ FOLLOW(A);
+			// A stays on the stack so the next iteration retries it.
+			sync := mergeSymbolSets(first[top.symbol], follow[top.symbol])
+			for p.current().Type != TOKEN_EOF && !tokenInSymbolSet(p.current(), sync) {
+				p.advance()
+			}
+			continue
+		}
+
+		stack = stack[:len(stack)-1]
+		prodBody := p.grammar.Productions[top.symbol].Body[idx]
+
+		children := make([]ll1StackEntry, len(prodBody))
+		for i, sym := range prodBody {
+			isTerm := sym == "ε" || sym == "epsilon" || !p.grammar.IsNonTerminal(sym)
+			childNode := p.newTreeNode(sym, isTerm)
+			children[i] = ll1StackEntry{symbol: sym, node: childNode}
+		}
+		top.node.Children = make([]*TreeNode, len(children))
+		for i, c := range children {
+			top.node.Children[i] = c.node
+		}
+
+		if p.recordSteps {
+			p.steps = append(p.steps, Step{
+				Action:         "expand",
+				Description:    fmt.Sprintf("Apply %s -> %s", top.symbol, strings.Join(prodBody, " ")),
+				NodeID:         top.node.ID,
+				Production:     prodBody,
+				Stack:          stackSymbols(stack),
+				RemainingInput: p.remainingInput(),
+			})
+		}
+
+		for i := len(children) - 1; i >= 0; i-- {
+			stack = append(stack, children[i])
+		}
+	}
+
+	if result.Error != "" {
+		result.Success = false
+		return result
+	}
+
+	result.Tree = root
+	result.Steps = p.steps
+	result.Conflicts = conflicts
+	result.Errors = p.errors
+	if len(p.errors) > 0 && result.Error == "" {
+		result.Error = p.errors[0].Message
+	}
+	return result
+}
+
+// stackSymbols extracts the symbol names from an LL(1) parse stack, for
+// display in the step trace.
+func stackSymbols(stack []ll1StackEntry) []string {
+	symbols := make([]string, len(stack))
+	for i, e := range stack {
+		symbols[i] = e.symbol
+	}
+	return symbols
+}
+
+// remainingInput returns the not-yet-consumed input tokens, space
+// separated, for display in the step trace.
+func (p *Parser) remainingInput() string {
+	parts := make([]string, 0, len(p.tokens)-p.pos)
+	for _, t := range p.tokens[p.pos:] {
+		if t.Type == TOKEN_EOF {
+			parts = append(parts, "$")
+			continue
+		}
+		parts = append(parts, t.Value)
+	}
+	return strings.Join(parts, " ")
+}
+
+// terminalKeyForToken returns the grammar terminal string that
+// corresponds to a lexer token, for LL(1)/SLR(1) table lookups.
+func terminalKeyForToken(tok Token) string {
+	switch tok.Type {
+	case TOKEN_NUMBER:
+		return "number"
+	case TOKEN_EOF:
+		return "$"
+	case TOKEN_PLUS:
+		return "+"
+	case TOKEN_MINUS:
+		return "-"
+	case TOKEN_MULT:
+		return "*"
+	case TOKEN_DIV:
+		return "/"
+	case TOKEN_LPAREN:
+		return "("
+	case TOKEN_RPAREN:
+		return ")"
+	default:
+		return tok.Value
+	}
+}
+
+// tokenInSymbolSet reports whether tok's grammar terminal key (or its
+// raw value, for identifier-like terminals) is a member of set. Used by
+// panic-mode recovery to test against FIRST/FOLLOW sync sets.
+func tokenInSymbolSet(tok Token, set map[string]bool) bool {
+	return set[terminalKeyForToken(tok)] || set[tok.Value]
+}
+
+// mergeSymbolSets returns the union of a and b as a new set.
+func mergeSymbolSets(a, b map[string]bool) map[string]bool {
+	merged := make(map[string]bool, len(a)+len(b))
+	for t := range a {
+		merged[t] = true
+	}
+	for t := range b {
+		merged[t] = true
+	}
+	return merged
+}
+
+// expectedTerminals returns the sorted set of lookahead terminals that
+// have an entry in row nonTerminal of table, for use as the Expected
+// field of a recovered ParseError.
+func expectedTerminals(table *LL1Table, nonTerminal string) []string {
+	row := table.Entries[nonTerminal]
+	out := make([]string, 0, len(row))
+	for t := range row {
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// tokenMatchesSymbol reports whether tok satisfies the grammar terminal
+// symbol, using the same rules as Parser.matchTerminal.
+func (p *Parser) tokenMatchesSymbol(tok Token, symbol string) bool {
+	switch symbol {
+	case "number":
+		return tok.Type == TOKEN_NUMBER
+	case "+":
+		return tok.Type == TOKEN_PLUS
+	case "-":
+		return tok.Type == TOKEN_MINUS
+	case "*":
+		return tok.Type == TOKEN_MULT
+	case "/":
+		return tok.Type == TOKEN_DIV
+	case "(":
+		return tok.Type == TOKEN_LPAREN
+	case ")":
+		return tok.Type == TOKEN_RPAREN
+	default:
+		return tok.Value == symbol
+	}
+}
@@ -0,0 +1,60 @@
+package parser
+
+import "testing"
+
+func TestComputeFirstFollowArithmetic(t *testing.T) {
+	grammar, err := ParseGrammar(GetDefaultArithmeticGrammar())
+	if err != nil {
+		t.Fatalf("ParseGrammar: %v", err)
+	}
+
+	first := ComputeFirst(grammar)
+	want := map[string]bool{"(": true, "number": true}
+	for t2 := range want {
+		if !first["F"][t2] {
+			t.Errorf("FIRST(F) missing %q, got %v", t2, first["F"])
+		}
+	}
+
+	follow := ComputeFollow(grammar, first)
+	if !follow["E"]["$"] {
+		t.Errorf("FOLLOW(E) should contain $, got %v", follow["E"])
+	}
+	if !follow["F"]["+"] || !follow["F"]["*"] {
+		t.Errorf("FOLLOW(F) should contain + and *, got %v", follow["F"])
+	}
+}
+
+func TestBuildLL1TableArithmeticHasNoConflicts(t *testing.T) {
+	grammar, err := ParseGrammar(GetDefaultArithmeticGrammar())
+	if err != nil {
+		t.Fatalf("ParseGrammar: %v", err)
+	}
+
+	_, conflicts, err := BuildLL1Table(grammar)
+	if err != nil {
+		t.Fatalf("BuildLL1Table: %v", err)
+	}
+	if len(conflicts) != 0 {
+		t.Fatalf("expected no conflicts for the default arithmetic grammar, got %v", conflicts)
+	}
+}
+
+func TestParseLL1Arithmetic(t *testing.T) {
+	grammar, err := ParseGrammar(GetDefaultArithmeticGrammar())
+	if err != nil {
+		t.Fatalf("ParseGrammar: %v", err)
+	}
+
+	p := NewParser(grammar)
+	result := p.ParseLL1("1 + 2 * 3", false, nil)
+	if !result.Success {
+		t.Fatalf("ParseLL1 failed: %s", result.Error)
+	}
+	if result.Tree == nil {
+		t.Fatal("expected a parse tree")
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("expected no recovered errors, got %v", result.Errors)
+	}
+}
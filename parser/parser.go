@@ -2,17 +2,32 @@ package parser
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
 // Parser implements a recursive descent parser for arithmetic expressions
 type Parser struct {
-	grammar    *Grammar
-	tokens     []Token
-	pos        int
-	nodeID     int
-	steps      []Step
+	grammar     *Grammar
+	tokens      []Token
+	pos         int
+	nodeID      int
+	steps       []Step
 	recordSteps bool
+	errors      []ParseError
+
+	// MaxErrors caps the number of diagnostics panic-mode recovery will
+	// record before giving up and failing the parse outright. Zero (the
+	// default) means unlimited.
+	MaxErrors int
+
+	// SyncSets overrides the synchronization token set used by
+	// recursive-descent panic-mode recovery for a given non-terminal.
+	// Non-terminals not present here fall back to FOLLOW(A).
+	SyncSets map[string][]string
+
+	first  map[string]map[string]bool
+	follow map[string]map[string]bool
 }
 
 // NewParser creates a new parser with the given grammar
@@ -27,15 +42,19 @@ func NewParser(grammar *Grammar) *Parser {
 	}
 }
 
-// Parse parses the input string and returns the parse result
-func (p *Parser) Parse(input string, recordSteps bool) *ParseResult {
+// Parse parses the input string and returns the parse result. A nil
+// lexerSpec falls back to DefaultLexerSpec.
+func (p *Parser) Parse(input string, recordSteps bool, lexerSpec *LexerSpec) *ParseResult {
 	// Tokenize input
-	lexer := NewLexer(input)
+	lexer := NewLexer(input, lexerSpec)
 	p.tokens = lexer.Tokenize()
 	p.pos = 0
 	p.nodeID = 0
 	p.steps = []Step{}
 	p.recordSteps = recordSteps
+	p.errors = []ParseError{}
+	p.first = ComputeFirst(p.grammar)
+	p.follow = ComputeFollow(p.grammar, p.first)
 
 	result := &ParseResult{
 		Success: true,
@@ -60,13 +79,17 @@ func (p *Parser) Parse(input string, recordSteps bool) *ParseResult {
 	// Check if all tokens were consumed
 	if p.current().Type != TOKEN_EOF {
 		result.Success = false
-		result.Error = fmt.Sprintf("Unexpected token '%s' at position %d", 
+		result.Error = fmt.Sprintf("Unexpected token '%s' at position %d",
 			p.current().Value, p.current().Position)
 		return result
 	}
 
 	result.Tree = tree
 	result.Steps = p.steps
+	result.Errors = p.errors
+	if len(p.errors) > 0 && result.Error == "" {
+		result.Error = p.errors[0].Message
+	}
 	return result
 }
 
@@ -77,6 +100,8 @@ func (p *Parser) parseNonTerminal(symbol string, parentID int) (*TreeNode, error
 		return nil, fmt.Errorf("undefined non-terminal: %s", symbol)
 	}
 
+	entryPos := p.pos
+
 	// Create node for this non-terminal
 	node := p.createNode(symbol, false, parentID)
 
@@ -97,8 +122,77 @@ func (p *Parser) parseNonTerminal(symbol string, parentID int) (*TreeNode, error
 		p.steps = p.steps[:savedStepsLen]
 	}
 
-	return nil, fmt.Errorf("no matching production for %s at position %d (found '%s')", 
-		symbol, p.pos, p.current().Value)
+	if p.maxErrorsReached() {
+		return nil, fmt.Errorf("no matching production for %s at position %d (found '%s')",
+			symbol, p.pos, p.current().Value)
+	}
+
+	// Panic-mode recovery: none of the alternatives matched. Record a
+	// diagnostic, mark the node so the tree still renders with an error
+	// marker, and skip input until a synchronizing token so parsing of
+	// the rest of the tree can continue.
+	tok := p.current()
+	p.errors = append(p.errors, ParseError{
+		Message:  fmt.Sprintf("no matching production for <%s>, found '%s'", symbol, tok.Value),
+		Position: tok.Position,
+		Line:     tok.Line,
+		Column:   tok.Column,
+		Expected: p.firstTerminals(symbol),
+		Found:    tok.Value,
+	})
+	node.IsError = true
+
+	sync := p.syncSet(symbol)
+	for p.current().Type != TOKEN_EOF && !tokenInSymbolSet(p.current(), sync) {
+		p.advance()
+	}
+
+	if p.pos == entryPos && p.current().Type == TOKEN_EOF {
+		// Recovery reached EOF without consuming a single token: there is
+		// nothing left to skip and nothing left to make progress with. If
+		// symbol is reachable again at this same position (e.g. a
+		// self-referential production like A -> X A), returning "success"
+		// here would let parseNonTerminal re-enter itself forever. Fail
+		// the parse instead of risking a stack overflow.
+		return nil, fmt.Errorf("no matching production for %s at position %d: input exhausted during recovery",
+			symbol, p.pos)
+	}
+
+	return node, nil
+}
+
+// firstTerminals returns the sorted FIRST set of symbol (excluding ε),
+// for use as the Expected field of a recovered ParseError.
+func (p *Parser) firstTerminals(symbol string) []string {
+	out := make([]string, 0, len(p.first[symbol]))
+	for t := range p.first[symbol] {
+		if t == "ε" {
+			continue
+		}
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// syncSet returns the synchronization token set used to recover from a
+// failed non-terminal: an explicit SyncSets override if present,
+// otherwise FOLLOW(symbol).
+func (p *Parser) syncSet(symbol string) map[string]bool {
+	if custom, ok := p.SyncSets[symbol]; ok {
+		set := make(map[string]bool, len(custom))
+		for _, s := range custom {
+			set[s] = true
+		}
+		return set
+	}
+	return p.follow[symbol]
+}
+
+// maxErrorsReached reports whether panic-mode recovery has already
+// recorded MaxErrors diagnostics (MaxErrors == 0 means unlimited).
+func (p *Parser) maxErrorsReached() bool {
+	return p.MaxErrors > 0 && len(p.errors) >= p.MaxErrors
 }
 
 // parseAlternative parses a single production alternative
@@ -202,15 +296,12 @@ func (p *Parser) advance() {
 	}
 }
 
-// createNode creates a new tree node and records a step if needed
+// createNode creates a new tree node and records an "add" step if
+// needed. Used by the recursive-descent Parse; the table-driven
+// ParseLL1/ParseSLR1 modes record their own stack-trace steps instead
+// and build nodes via newTreeNode to avoid double-recording.
 func (p *Parser) createNode(label string, isTerminal bool, parentID int) *TreeNode {
-	p.nodeID++
-	node := &TreeNode{
-		ID:         p.nodeID,
-		Label:      label,
-		Children:   []*TreeNode{},
-		IsTerminal: isTerminal,
-	}
+	node := p.newTreeNode(label, isTerminal)
 
 	if p.recordSteps {
 		step := Step{
@@ -225,6 +316,18 @@ func (p *Parser) createNode(label string, isTerminal bool, parentID int) *TreeNo
 	return node
 }
 
+// newTreeNode allocates a new tree node without recording a step.
+func (p *Parser) newTreeNode(label string, isTerminal bool) *TreeNode {
+	p.nodeID++
+	return &TreeNode{
+		ID:          p.nodeID,
+		Label:       label,
+		Children:    []*TreeNode{},
+		IsTerminal:  isTerminal,
+		SugaredForm: sugaredFormFor(label),
+	}
+}
+
 // buildStepDescription creates a human-readable description for a step
 func (p *Parser) buildStepDescription(label string, isTerminal bool) string {
 	if isTerminal {
@@ -248,7 +351,7 @@ func ParseWithDefaultGrammar(input string, recordSteps bool) *ParseResult {
 	}
 
 	parser := NewParser(grammar)
-	return parser.Parse(input, recordSteps)
+	return parser.Parse(input, recordSteps, nil)
 }
 
 // TreeToString returns a string representation of the parse tree
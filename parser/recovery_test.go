@@ -0,0 +1,99 @@
+package parser
+
+import "testing"
+
+// treeHasError reports whether any node in the tree rooted at n has
+// IsError set.
+func treeHasError(n *TreeNode) bool {
+	if n == nil {
+		return false
+	}
+	if n.IsError {
+		return true
+	}
+	for _, c := range n.Children {
+		if treeHasError(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// Regression/behavior test: Parse used to fail outright the moment a
+// non-terminal had no matching alternative. Panic-mode recovery should
+// instead record a ParseError, mark the offending node, skip to a
+// synchronizing token, and let the rest of the input still parse.
+func TestParseRecoversFromUnexpectedToken(t *testing.T) {
+	grammar, err := ParseGrammar(GetDefaultArithmeticGrammar())
+	if err != nil {
+		t.Fatalf("ParseGrammar: %v", err)
+	}
+
+	p := NewParser(grammar)
+	result := p.Parse("1 + + 2", false, nil)
+	if !result.Success {
+		t.Fatalf("Parse failed: %s", result.Error)
+	}
+	if len(result.Errors) != 1 {
+		t.Fatalf("expected exactly one recovered error, got %v", result.Errors)
+	}
+	if !treeHasError(result.Tree) {
+		t.Error("expected some node in the tree to be marked IsError")
+	}
+}
+
+// MaxErrors should cap recovery attempts: once the cap is hit, a further
+// unmatched non-terminal fails the parse outright instead of recovering.
+func TestParseMaxErrorsStopsRecovering(t *testing.T) {
+	grammar, err := ParseGrammar(GetDefaultArithmeticGrammar())
+	if err != nil {
+		t.Fatalf("ParseGrammar: %v", err)
+	}
+
+	p := NewParser(grammar)
+	p.MaxErrors = 1
+	result := p.Parse("+ + 2", false, nil)
+	if result.Success {
+		t.Fatal("expected Parse to fail once MaxErrors recovery attempts are exhausted")
+	}
+}
+
+// Regression test: a production shaped A -> X A (self-referential, with
+// X itself unmatchable here) used to crash the process with a stack
+// overflow. Recovery for X would "succeed" without consuming a token at
+// EOF, letting the enclosing A re-enter itself at the same position
+// forever. It must now fail the parse instead.
+func TestParseFailsInsteadOfOverflowingOnSelfReferentialRecovery(t *testing.T) {
+	grammar, err := ParseGrammar(`S -> A S
+A -> x`)
+	if err != nil {
+		t.Fatalf("ParseGrammar: %v", err)
+	}
+
+	p := NewParser(grammar)
+	result := p.Parse("", false, nil)
+	if result.Success {
+		t.Fatal("expected Parse to fail rather than loop forever")
+	}
+}
+
+// Regression/behavior test: the LL(1) table-driven parser gets the same
+// panic-mode recovery as the recursive-descent one.
+func TestParseLL1RecoversFromUnexpectedToken(t *testing.T) {
+	grammar, err := ParseGrammar(GetDefaultArithmeticGrammar())
+	if err != nil {
+		t.Fatalf("ParseGrammar: %v", err)
+	}
+
+	p := NewParser(grammar)
+	result := p.ParseLL1("1 + + 2", false, nil)
+	if !result.Success {
+		t.Fatalf("ParseLL1 failed: %s", result.Error)
+	}
+	if len(result.Errors) != 1 {
+		t.Fatalf("expected exactly one recovered error, got %v", result.Errors)
+	}
+	if !treeHasError(result.Tree) {
+		t.Error("expected some node in the tree to be marked IsError")
+	}
+}
@@ -0,0 +1,433 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Item is an LR(0) item: a production with a dot marking how much of
+// its body has been matched so far.
+type Item struct {
+	Head string   `json:"head"`
+	Body []string `json:"body"`
+	Dot  int      `json:"dot"`
+}
+
+// ItemSet is a canonical LR(0) item set (a DFA state).
+type ItemSet struct {
+	ID    int    `json:"id"`
+	Items []Item `json:"items"`
+}
+
+// LR0Automaton is the canonical collection of LR(0) item sets and the
+// goto transitions between them, as built by BuildSLR1.
+type LR0Automaton struct {
+	States         []ItemSet              `json:"states"`
+	Transitions    map[int]map[string]int `json:"transitions"` // state -> symbol -> next state
+	StartState     int                    `json:"startState"`
+	AugmentedStart string                 `json:"augmentedStart"`
+}
+
+// SLRAction is one ACTION table cell: a shift, a reduce, or accept.
+type SLRAction struct {
+	Type       string   `json:"type"` // "shift" | "reduce" | "accept"
+	State      int      `json:"state,omitempty"`
+	ReduceHead string   `json:"reduceHead,omitempty"`
+	ReduceBody []string `json:"reduceBody,omitempty"`
+}
+
+// SLRTable holds the ACTION and GOTO tables produced by BuildSLR1.
+type SLRTable struct {
+	Action map[int]map[string]SLRAction `json:"action"`
+	Goto   map[int]map[string]int       `json:"goto"`
+}
+
+// slrFrame pairs a parser stack state with the tree node reduced onto it.
+type slrFrame struct {
+	state int
+	node  *TreeNode
+}
+
+// BuildSLR1 builds the canonical LR(0) collection and SLR(1) ACTION/GOTO
+// tables for g. Shift/reduce and reduce/reduce conflicts are reported
+// instead of silently resolved.
+func BuildSLR1(g *Grammar) (*LR0Automaton, *SLRTable, []Conflict, error) {
+	if g.StartSymbol == "" {
+		return nil, nil, nil, fmt.Errorf("grammar has no start symbol")
+	}
+
+	aug := cloneGrammar(g)
+	augStart := freshNonTerminal(aug, g.StartSymbol+"'")
+	aug.Productions[augStart] = &Production{Head: augStart, Body: [][]string{{g.StartSymbol}}}
+	aug.NonTerminals[augStart] = true
+	aug.Order = append([]string{augStart}, aug.Order...)
+	aug.StartSymbol = augStart
+
+	first := ComputeFirst(aug)
+	follow := ComputeFollow(aug, first)
+
+	states, transitions := buildCanonicalCollection(aug, augStart, g.StartSymbol)
+	table, conflicts := buildSLRTable(aug, augStart, states, transitions, follow)
+
+	automaton := &LR0Automaton{
+		States:         states,
+		Transitions:    transitions,
+		StartState:     0,
+		AugmentedStart: augStart,
+	}
+
+	return automaton, table, conflicts, nil
+}
+
+// buildCanonicalCollection builds the canonical collection of LR(0) item
+// sets, starting from closure({augStart -> . origStart}).
+func buildCanonicalCollection(aug *Grammar, augStart, origStart string) ([]ItemSet, map[int]map[string]int) {
+	startItems := closure(aug, []Item{{Head: augStart, Body: []string{origStart}, Dot: 0}})
+
+	states := []ItemSet{{ID: 0, Items: startItems}}
+	index := map[string]int{itemSetKey(startItems): 0}
+	transitions := map[int]map[string]int{}
+
+	queue := []int{0}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		items := states[id].Items
+
+		seen := map[string]bool{}
+		var symbols []string
+		for _, it := range items {
+			if it.Dot >= len(it.Body) {
+				continue
+			}
+			sym := it.Body[it.Dot]
+			if !seen[sym] {
+				seen[sym] = true
+				symbols = append(symbols, sym)
+			}
+		}
+		sort.Strings(symbols)
+
+		for _, sym := range symbols {
+			target := gotoSet(aug, items, sym)
+			if len(target) == 0 {
+				continue
+			}
+			key := itemSetKey(target)
+			targetID, exists := index[key]
+			if !exists {
+				targetID = len(states)
+				states = append(states, ItemSet{ID: targetID, Items: target})
+				index[key] = targetID
+				queue = append(queue, targetID)
+			}
+			if transitions[id] == nil {
+				transitions[id] = map[string]int{}
+			}
+			transitions[id][sym] = targetID
+		}
+	}
+
+	return states, transitions
+}
+
+// closure computes the LR(0) closure of a set of items: for every item
+// A -> alpha . B beta with B a non-terminal, add B -> . gamma for every
+// production B -> gamma, iterated to a fixed point.
+func closure(aug *Grammar, items []Item) []Item {
+	set := make(map[string]Item)
+	for _, it := range items {
+		set[itemKey(it)] = it
+	}
+
+	changed := true
+	for changed {
+		changed = false
+		for _, it := range set {
+			if it.Dot >= len(it.Body) {
+				continue
+			}
+			sym := it.Body[it.Dot]
+			if !aug.IsNonTerminal(sym) {
+				continue
+			}
+			prod := aug.Productions[sym]
+			if prod == nil {
+				continue
+			}
+			for _, alt := range prod.Body {
+				newItem := Item{Head: sym, Body: normalizeProductionBody(alt), Dot: 0}
+				key := itemKey(newItem)
+				if _, ok := set[key]; !ok {
+					set[key] = newItem
+					changed = true
+				}
+			}
+		}
+	}
+
+	return sortedItems(set)
+}
+
+// gotoSet advances every item in items whose next symbol is symbol and
+// returns the closure of the result (nil if no item advances).
+func gotoSet(aug *Grammar, items []Item, symbol string) []Item {
+	moved := []Item{}
+	for _, it := range items {
+		if it.Dot < len(it.Body) && it.Body[it.Dot] == symbol {
+			moved = append(moved, Item{Head: it.Head, Body: it.Body, Dot: it.Dot + 1})
+		}
+	}
+	if len(moved) == 0 {
+		return nil
+	}
+	return closure(aug, moved)
+}
+
+// buildSLRTable fills the ACTION/GOTO tables from the canonical
+// collection: shifts and gotos come from transitions, reduces (and
+// accept) come from complete items, using FOLLOW to decide reduce
+// lookaheads.
+func buildSLRTable(aug *Grammar, augStart string, states []ItemSet, transitions map[int]map[string]int, follow map[string]map[string]bool) (*SLRTable, []Conflict) {
+	table := &SLRTable{Action: map[int]map[string]SLRAction{}, Goto: map[int]map[string]int{}}
+	conflicts := []Conflict{}
+
+	for _, state := range states {
+		table.Action[state.ID] = map[string]SLRAction{}
+		table.Goto[state.ID] = map[string]int{}
+
+		for sym, target := range transitions[state.ID] {
+			if aug.IsNonTerminal(sym) {
+				table.Goto[state.ID][sym] = target
+				continue
+			}
+			setSLRAction(table, &conflicts, state.ID, sym, SLRAction{Type: "shift", State: target})
+		}
+
+		for _, it := range state.Items {
+			if it.Dot != len(it.Body) {
+				continue
+			}
+			if it.Head == augStart {
+				setSLRAction(table, &conflicts, state.ID, "$", SLRAction{Type: "accept"})
+				continue
+			}
+			for t := range follow[it.Head] {
+				if t == "ε" {
+					continue
+				}
+				setSLRAction(table, &conflicts, state.ID, t, SLRAction{
+					Type:       "reduce",
+					ReduceHead: it.Head,
+					ReduceBody: append([]string{}, it.Body...),
+				})
+			}
+		}
+	}
+
+	return table, conflicts
+}
+
+// setSLRAction records action in cell (state, terminal), or reports a
+// Conflict if the cell already holds a different action.
+func setSLRAction(table *SLRTable, conflicts *[]Conflict, state int, terminal string, action SLRAction) {
+	if existing, ok := table.Action[state][terminal]; ok {
+		if !sameSLRAction(existing, action) {
+			*conflicts = append(*conflicts, Conflict{
+				Terminal:    terminal,
+				State:       state,
+				Production1: describeSLRAction(existing),
+				Production2: describeSLRAction(action),
+			})
+		}
+		return
+	}
+	table.Action[state][terminal] = action
+}
+
+func sameSLRAction(a, b SLRAction) bool {
+	if a.Type != b.Type || a.State != b.State || a.ReduceHead != b.ReduceHead {
+		return false
+	}
+	if len(a.ReduceBody) != len(b.ReduceBody) {
+		return false
+	}
+	for i := range a.ReduceBody {
+		if a.ReduceBody[i] != b.ReduceBody[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func describeSLRAction(a SLRAction) []string {
+	switch a.Type {
+	case "shift":
+		return []string{"shift", strconv.Itoa(a.State)}
+	case "reduce":
+		return append([]string{a.ReduceHead, "->"}, a.ReduceBody...)
+	default:
+		return []string{a.Type}
+	}
+}
+
+// normalizeProductionBody turns an explicit epsilon alternative (["ε"])
+// into an empty body, so epsilon items are immediately complete.
+func normalizeProductionBody(alt []string) []string {
+	if len(alt) == 1 && (alt[0] == "ε" || alt[0] == "epsilon") {
+		return []string{}
+	}
+	return alt
+}
+
+// itemKey renders an item as a canonical string for set membership and
+// item-set identity.
+func itemKey(it Item) string {
+	return fmt.Sprintf("%s -> %s . %s", it.Head, strings.Join(it.Body[:it.Dot], " "), strings.Join(it.Body[it.Dot:], " "))
+}
+
+// sortedItems renders a closure's item set as a deterministically
+// ordered slice.
+func sortedItems(set map[string]Item) []Item {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	items := make([]Item, len(keys))
+	for i, k := range keys {
+		items[i] = set[k]
+	}
+	return items
+}
+
+// itemSetKey renders a (pre-sorted) item slice as a single string,
+// identifying the canonical item set it represents.
+func itemSetKey(items []Item) string {
+	parts := make([]string, len(items))
+	for i, it := range items {
+		parts[i] = itemKey(it)
+	}
+	return strings.Join(parts, "|")
+}
+
+// ParseSLR1 drives the shift/reduce SLR(1) algorithm over input,
+// recording one Step per shift/reduce when recordSteps is set. The
+// canonical item sets and transitions are attached to the result as
+// ParseResult.Automaton so the frontend can draw the DFA. A nil
+// lexerSpec falls back to DeriveLexerSpec(p.grammar).
+func (p *Parser) ParseSLR1(input string, recordSteps bool, lexerSpec *LexerSpec) *ParseResult {
+	if lexerSpec == nil {
+		lexerSpec = DeriveLexerSpec(p.grammar)
+	}
+	lexer := NewLexer(input, lexerSpec)
+	p.tokens = lexer.Tokenize()
+	p.pos = 0
+	p.nodeID = 0
+	p.steps = []Step{}
+	p.recordSteps = recordSteps
+
+	result := &ParseResult{Success: true, Tokens: p.tokens, Steps: []Step{}}
+
+	if p.grammar.StartSymbol == "" {
+		result.Success = false
+		result.Error = "Grammar has no start symbol"
+		return result
+	}
+
+	automaton, table, conflicts, err := BuildSLR1(p.grammar)
+	if err != nil {
+		result.Success = false
+		result.Error = err.Error()
+		return result
+	}
+	result.Automaton = automaton
+	result.Conflicts = conflicts
+
+	stack := []slrFrame{{state: 0}}
+
+parseLoop:
+	for {
+		state := stack[len(stack)-1].state
+		tok := p.current()
+		key := terminalKeyForToken(tok)
+
+		action, ok := table.Action[state][key]
+		if !ok {
+			result.Success = false
+			result.Error = fmt.Sprintf("no action for state %d on input '%s' at position %d", state, tok.Value, tok.Position)
+			break parseLoop
+		}
+
+		switch action.Type {
+		case "shift":
+			node := p.newTreeNode(tok.Value, true)
+			stack = append(stack, slrFrame{state: action.State, node: node})
+			if p.recordSteps {
+				p.steps = append(p.steps, Step{
+					Action:         "shift",
+					Description:    fmt.Sprintf("Shift '%s', go to state %d", tok.Value, action.State),
+					NodeID:         node.ID,
+					Stack:          slrStackStates(stack),
+					RemainingInput: p.remainingInput(),
+				})
+			}
+			p.advance()
+
+		case "reduce":
+			n := len(action.ReduceBody)
+			var children []*TreeNode
+			if n == 0 {
+				children = []*TreeNode{p.newTreeNode("ε", true)}
+			} else {
+				children = make([]*TreeNode, n)
+				for i := 0; i < n; i++ {
+					children[i] = stack[len(stack)-n+i].node
+				}
+				stack = stack[:len(stack)-n]
+			}
+
+			parentNode := p.newTreeNode(action.ReduceHead, false)
+			parentNode.Children = children
+
+			gotoState, ok := table.Goto[stack[len(stack)-1].state][action.ReduceHead]
+			if !ok {
+				result.Success = false
+				result.Error = fmt.Sprintf("no goto for state %d on %s", stack[len(stack)-1].state, action.ReduceHead)
+				break parseLoop
+			}
+			stack = append(stack, slrFrame{state: gotoState, node: parentNode})
+
+			if p.recordSteps {
+				p.steps = append(p.steps, Step{
+					Action:         "reduce",
+					Description:    fmt.Sprintf("Reduce by %s -> %s", action.ReduceHead, strings.Join(action.ReduceBody, " ")),
+					NodeID:         parentNode.ID,
+					Production:     action.ReduceBody,
+					Stack:          slrStackStates(stack),
+					RemainingInput: p.remainingInput(),
+				})
+			}
+
+		case "accept":
+			result.Tree = stack[len(stack)-1].node
+			result.Steps = p.steps
+			return result
+		}
+	}
+
+	return result
+}
+
+// slrStackStates extracts the state numbers from an SLR parse stack, for
+// display in the step trace.
+func slrStackStates(stack []slrFrame) []string {
+	labels := make([]string, len(stack))
+	for i, f := range stack {
+		labels[i] = strconv.Itoa(f.state)
+	}
+	return labels
+}
@@ -0,0 +1,69 @@
+package parser
+
+import "testing"
+
+func TestBuildSLR1ArithmeticHasNoConflicts(t *testing.T) {
+	grammar, err := ParseGrammar(`E -> E + T | T
+T -> T * F | F
+F -> "(" E ")" | number`)
+	if err != nil {
+		t.Fatalf("ParseGrammar: %v", err)
+	}
+
+	automaton, _, conflicts, err := BuildSLR1(grammar)
+	if err != nil {
+		t.Fatalf("BuildSLR1: %v", err)
+	}
+	if len(conflicts) != 0 {
+		t.Fatalf("expected no shift/reduce conflicts, got %v", conflicts)
+	}
+	if len(automaton.States) == 0 {
+		t.Fatal("expected at least one LR(0) item set")
+	}
+}
+
+func TestParseSLR1Arithmetic(t *testing.T) {
+	grammar, err := ParseGrammar(`E -> E + T | T
+T -> T * F | F
+F -> "(" E ")" | number`)
+	if err != nil {
+		t.Fatalf("ParseGrammar: %v", err)
+	}
+
+	p := NewParser(grammar)
+	result := p.ParseSLR1("1 + 2 * 3", false, nil)
+	if !result.Success {
+		t.Fatalf("ParseSLR1 failed: %s", result.Error)
+	}
+	if result.Tree == nil {
+		t.Fatal("expected a parse tree")
+	}
+	if result.Automaton == nil {
+		t.Fatal("expected the LR(0) automaton to be attached to the result")
+	}
+}
+
+// Regression test: ParseSLR1 used to hardcode DeriveLexerSpec(p.grammar),
+// silently ignoring any explicit lexerSpec override. DeriveLexerSpec would
+// map the "+" terminal to the literal regex `\+`, which can't tokenize the
+// word "plus" below, so this only parses if the custom spec is actually
+// threaded through.
+func TestParseSLR1RespectsLexerSpecOverride(t *testing.T) {
+	grammar, err := ParseGrammar(`E -> T "+" T | T
+T -> number`)
+	if err != nil {
+		t.Fatalf("ParseGrammar: %v", err)
+	}
+
+	custom := &LexerSpec{Rules: []TokenRule{
+		{Name: "skip", Pattern: `[ \t\r\n]+`, Skip: true},
+		{Name: "number", Pattern: `[0-9]+`},
+		{Name: "+", Pattern: `plus`},
+	}}
+
+	p := NewParser(grammar)
+	result := p.ParseSLR1("1 plus 2", false, custom)
+	if !result.Success {
+		t.Fatalf("ParseSLR1 with custom lexerSpec failed: %s", result.Error)
+	}
+}
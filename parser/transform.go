@@ -0,0 +1,353 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TransformStep describes a single rewrite applied by EliminateLeftRecursion
+// or LeftFactor, for display in the visualizer.
+type TransformStep struct {
+	Kind        string `json:"kind"` // "left-recursion" or "left-factor"
+	NonTerminal string `json:"nonTerminal"`
+	Description string `json:"description"`
+}
+
+// TransformOptions selects which grammar-rewriting passes to run.
+type TransformOptions struct {
+	EliminateLeftRecursion bool `json:"eliminateLeftRecursion"`
+	LeftFactor             bool `json:"leftFactor"`
+}
+
+// EliminateLeftRecursion rewrites g to remove direct and indirect left
+// recursion using the standard textbook algorithm, and returns a new
+// Grammar (g is left untouched). Applied rewrites are recorded on the
+// result's TransformSteps.
+func EliminateLeftRecursion(g *Grammar) *Grammar {
+	out := cloneGrammar(g)
+	order := grammarOrder(out)
+	steps := []TransformStep{}
+
+	for i, ai := range order {
+		// Substitute productions of earlier non-terminals into any
+		// Ai -> Aj gamma to expose indirect recursion as direct recursion.
+		for j := 0; j < i; j++ {
+			aj := order[j]
+			prod := out.Productions[ai]
+			if prod == nil {
+				continue
+			}
+
+			newBodies := [][]string{}
+			substituted := false
+
+			for _, alt := range prod.Body {
+				if len(alt) == 0 || alt[0] != aj {
+					newBodies = append(newBodies, alt)
+					continue
+				}
+
+				substituted = true
+				ajProd := out.Productions[aj]
+				for _, ajAlt := range ajProd.Body {
+					var newAlt []string
+					if len(ajAlt) == 1 && (ajAlt[0] == "ε" || ajAlt[0] == "epsilon") {
+						newAlt = append([]string{}, alt[1:]...)
+					} else {
+						newAlt = append(append([]string{}, ajAlt...), alt[1:]...)
+					}
+					newBodies = append(newBodies, newAlt)
+				}
+			}
+
+			if substituted {
+				prod.Body = newBodies
+				steps = append(steps, TransformStep{
+					Kind:        "left-recursion",
+					NonTerminal: ai,
+					Description: fmt.Sprintf("Substituted %s into %s to expose indirect left recursion", aj, ai),
+				})
+			}
+		}
+
+		// Eliminate immediate left recursion on Ai.
+		prod := out.Productions[ai]
+		if prod == nil {
+			continue
+		}
+
+		recursive := [][]string{}
+		nonRecursive := [][]string{}
+		for _, alt := range prod.Body {
+			if len(alt) > 0 && alt[0] == ai {
+				recursive = append(recursive, alt[1:])
+			} else {
+				nonRecursive = append(nonRecursive, alt)
+			}
+		}
+
+		if len(recursive) == 0 {
+			continue
+		}
+
+		newName := freshNonTerminal(out, ai+"'")
+
+		newAiBodies := make([][]string, 0, len(nonRecursive))
+		for _, alt := range nonRecursive {
+			newAiBodies = append(newAiBodies, append(append([]string{}, alt...), newName))
+		}
+
+		newPrimeBodies := make([][]string, 0, len(recursive)+1)
+		for _, alt := range recursive {
+			newPrimeBodies = append(newPrimeBodies, append(append([]string{}, alt...), newName))
+		}
+		newPrimeBodies = append(newPrimeBodies, []string{"ε"})
+
+		prod.Body = newAiBodies
+		out.Productions[newName] = &Production{Head: newName, Body: newPrimeBodies}
+		out.NonTerminals[newName] = true
+		out.Order = insertStringAfter(out.Order, ai, newName)
+
+		steps = append(steps, TransformStep{
+			Kind:        "left-recursion",
+			NonTerminal: ai,
+			Description: fmt.Sprintf("Eliminated immediate left recursion on %s by introducing %s", ai, newName),
+		})
+	}
+
+	recomputeTerminals(out)
+	out.TransformSteps = append(out.TransformSteps, steps...)
+	return out
+}
+
+// LeftFactor rewrites g so that no non-terminal has two alternatives
+// sharing a common prefix, introducing fresh non-terminals as needed.
+// It returns a new Grammar (g is left untouched).
+func LeftFactor(g *Grammar) *Grammar {
+	out := cloneGrammar(g)
+	order := grammarOrder(out)
+	steps := []TransformStep{}
+
+	i := 0
+	for i < len(order) {
+		head := order[i]
+		prod := out.Productions[head]
+
+		for prod != nil {
+			prefixLen, idxs := longestCommonPrefixGroup(prod.Body)
+			if prefixLen == 0 {
+				break
+			}
+
+			prefix := append([]string{}, prod.Body[idxs[0]][:prefixLen]...)
+			newName := freshNonTerminal(out, head+"'")
+
+			inGroup := make(map[int]bool, len(idxs))
+			for _, idx := range idxs {
+				inGroup[idx] = true
+			}
+
+			newBodies := [][]string{}
+			for idx, alt := range prod.Body {
+				if inGroup[idx] {
+					continue
+				}
+				newBodies = append(newBodies, alt)
+			}
+			newBodies = append(newBodies, append(append([]string{}, prefix...), newName))
+
+			newAltBodies := make([][]string, 0, len(idxs))
+			for _, idx := range idxs {
+				suffix := prod.Body[idx][prefixLen:]
+				if len(suffix) == 0 {
+					suffix = []string{"ε"}
+				} else {
+					suffix = append([]string{}, suffix...)
+				}
+				newAltBodies = append(newAltBodies, suffix)
+			}
+
+			prod.Body = newBodies
+			out.Productions[newName] = &Production{Head: newName, Body: newAltBodies}
+			out.NonTerminals[newName] = true
+			out.Order = insertStringAfter(out.Order, head, newName)
+			order = insertStringAfter(order, head, newName)
+
+			steps = append(steps, TransformStep{
+				Kind:        "left-factor",
+				NonTerminal: head,
+				Description: fmt.Sprintf("Factored common prefix '%s' out of %s into %s", strings.Join(prefix, " "), head, newName),
+			})
+		}
+
+		i++
+	}
+
+	recomputeTerminals(out)
+	out.TransformSteps = append(out.TransformSteps, steps...)
+	return out
+}
+
+// longestCommonPrefixGroup finds the first group of two or more
+// alternatives in bodies that share a common first symbol and returns
+// the length of their longest common prefix along with the indices of
+// the alternatives in that group. It returns (0, nil) if no such group
+// exists.
+func longestCommonPrefixGroup(bodies [][]string) (int, []int) {
+	for start := 0; start < len(bodies); start++ {
+		if len(bodies[start]) == 0 {
+			continue
+		}
+
+		idxs := []int{start}
+		for j := start + 1; j < len(bodies); j++ {
+			if len(bodies[j]) > 0 && bodies[j][0] == bodies[start][0] {
+				idxs = append(idxs, j)
+			}
+		}
+		if len(idxs) < 2 {
+			continue
+		}
+
+		prefixLen := 1
+		for {
+			extendable := true
+			for _, idx := range idxs {
+				if prefixLen >= len(bodies[idx]) || bodies[idx][prefixLen] != bodies[start][prefixLen] {
+					extendable = false
+					break
+				}
+			}
+			if !extendable {
+				break
+			}
+			prefixLen++
+		}
+
+		return prefixLen, idxs
+	}
+
+	return 0, nil
+}
+
+// cloneGrammar returns a deep copy of g so transforms never mutate their
+// input.
+func cloneGrammar(g *Grammar) *Grammar {
+	out := &Grammar{
+		Productions:  make(map[string]*Production, len(g.Productions)),
+		StartSymbol:  g.StartSymbol,
+		Terminals:    make(map[string]bool, len(g.Terminals)),
+		NonTerminals: make(map[string]bool, len(g.NonTerminals)),
+		Order:        append([]string{}, g.Order...),
+	}
+	for head, prod := range g.Productions {
+		body := make([][]string, len(prod.Body))
+		for i, alt := range prod.Body {
+			body[i] = append([]string{}, alt...)
+		}
+		out.Productions[head] = &Production{Head: head, Body: body}
+	}
+	for t := range g.Terminals {
+		out.Terminals[t] = true
+	}
+	for nt := range g.NonTerminals {
+		out.NonTerminals[nt] = true
+	}
+	return out
+}
+
+// grammarOrder returns the non-terminal heads of g in first-seen order,
+// falling back to map iteration for grammars built without Order set.
+func grammarOrder(g *Grammar) []string {
+	if len(g.Order) > 0 {
+		return append([]string{}, g.Order...)
+	}
+	order := make([]string, 0, len(g.NonTerminals))
+	for nt := range g.NonTerminals {
+		order = append(order, nt)
+	}
+	return order
+}
+
+// freshNonTerminal appends "'" to base until it no longer collides with
+// an existing non-terminal in g.
+func freshNonTerminal(g *Grammar, base string) string {
+	name := base
+	for g.NonTerminals[name] {
+		name += "'"
+	}
+	return name
+}
+
+// insertStringAfter returns a copy of s with val inserted immediately
+// after the first occurrence of after (or appended if after is absent).
+func insertStringAfter(s []string, after, val string) []string {
+	for i, v := range s {
+		if v == after {
+			out := make([]string, 0, len(s)+1)
+			out = append(out, s[:i+1]...)
+			out = append(out, val)
+			out = append(out, s[i+1:]...)
+			return out
+		}
+	}
+	return append(append([]string{}, s...), val)
+}
+
+// recomputeTerminals rebuilds g.Terminals from scratch by scanning every
+// production body, the same way ParseGrammar derives terminals.
+func recomputeTerminals(g *Grammar) {
+	g.Terminals = make(map[string]bool)
+	for _, prod := range g.Productions {
+		for _, alt := range prod.Body {
+			for _, symbol := range alt {
+				if isSugarSymbol(symbol) {
+					continue
+				}
+				if !g.NonTerminals[symbol] && symbol != "ε" && symbol != "epsilon" {
+					g.Terminals[symbol] = true
+				}
+			}
+		}
+	}
+}
+
+// GrammarToString renders g back into the BNF source syntax accepted by
+// ParseGrammar, using Order to keep output stable across transforms.
+func GrammarToString(g *Grammar) string {
+	var sb strings.Builder
+
+	order := grammarOrder(g)
+	seen := make(map[string]bool, len(order))
+	for _, head := range order {
+		seen[head] = true
+	}
+	for nt := range g.NonTerminals {
+		if !seen[nt] {
+			order = append(order, nt)
+		}
+	}
+
+	for _, head := range order {
+		prod, ok := g.Productions[head]
+		if !ok {
+			continue
+		}
+		alts := make([]string, len(prod.Body))
+		for i, alt := range prod.Body {
+			syms := make([]string, len(alt))
+			for k, sym := range alt {
+				syms[k] = sym
+				// Quote terminals that ParseGrammar would otherwise read as
+				// EBNF brackets, an alternative separator, or several symbols.
+				if g.Terminals[sym] && strings.ContainsAny(sym, "()[]{}| \t") {
+					syms[k] = `"` + sym + `"`
+				}
+			}
+			alts[i] = strings.Join(syms, " ")
+		}
+		sb.WriteString(fmt.Sprintf("%s -> %s\n", head, strings.Join(alts, " | ")))
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
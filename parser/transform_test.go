@@ -0,0 +1,57 @@
+package parser
+
+import "testing"
+
+// left-recursive grammar equivalent to the default arithmetic grammar,
+// written the "naive" textbook way.
+const leftRecursiveArithmetic = `E -> E + T | T
+T -> T * F | F
+F -> "(" E ")" | number`
+
+func TestEliminateLeftRecursionRemovesDirectRecursion(t *testing.T) {
+	grammar, err := ParseGrammar(leftRecursiveArithmetic)
+	if err != nil {
+		t.Fatalf("ParseGrammar: %v", err)
+	}
+
+	out := EliminateLeftRecursion(grammar)
+
+	for head, prod := range out.Productions {
+		for _, alt := range prod.Body {
+			if len(alt) > 0 && alt[0] == head {
+				t.Errorf("production %s still left-recursive: %v", head, alt)
+			}
+		}
+	}
+	if len(out.TransformSteps) == 0 {
+		t.Error("expected at least one recorded TransformStep")
+	}
+
+	// the grammar should still be parseable after the rewrite and should
+	// now build an LL(1) table with no conflicts.
+	if _, conflicts, err := BuildLL1Table(out); err != nil || len(conflicts) != 0 {
+		t.Errorf("expected conflict-free LL(1) table after rewrite, conflicts=%v err=%v", conflicts, err)
+	}
+}
+
+func TestLeftFactorFactorsCommonPrefix(t *testing.T) {
+	grammar, err := ParseGrammar(`S -> if E then S | if E then S else S | other`)
+	if err != nil {
+		t.Fatalf("ParseGrammar: %v", err)
+	}
+
+	out := LeftFactor(grammar)
+
+	ifAlts := 0
+	for _, alt := range out.Productions["S"].Body {
+		if len(alt) > 1 && alt[0] == "if" && alt[1] == "E" {
+			ifAlts++
+		}
+	}
+	if ifAlts != 1 {
+		t.Errorf("expected S's two 'if' alternatives to be merged into one, found %d: %v", ifAlts, out.Productions["S"].Body)
+	}
+	if len(out.TransformSteps) == 0 {
+		t.Error("expected at least one recorded TransformStep")
+	}
+}
@@ -22,52 +22,79 @@ type Token struct {
 	Type     TokenType `json:"type"`
 	Value    string    `json:"value"`
 	Position int       `json:"position"`
+	Line     int       `json:"line"`
+	Column   int       `json:"column"`
 }
 
 // TreeNode represents a node in the parse tree
 type TreeNode struct {
-	ID         int         `json:"id"`
-	Label      string      `json:"label"`
-	Children   []*TreeNode `json:"children"`
-	IsTerminal bool        `json:"isTerminal"`
-	Value      string      `json:"value,omitempty"`
+	ID          int         `json:"id"`
+	Label       string      `json:"label"`
+	Children    []*TreeNode `json:"children"`
+	IsTerminal  bool        `json:"isTerminal"`
+	Value       string      `json:"value,omitempty"`
+	SugaredForm string      `json:"sugaredForm,omitempty"` // e.g. "?" or "*" for nodes introduced by Grammar.Desugar()
+	IsError     bool        `json:"isError,omitempty"`     // marks a node where panic-mode recovery was applied (input may or may not have been skipped)
+}
+
+// ParseError is a single diagnostic produced while parsing, including
+// any recovered from by panic-mode synchronization.
+type ParseError struct {
+	Message  string   `json:"message"`
+	Position int      `json:"position"`
+	Line     int      `json:"line"`
+	Column   int      `json:"column"`
+	Expected []string `json:"expected,omitempty"`
+	Found    string   `json:"found"`
 }
 
 // Step represents a single step in the parsing process for animation
 type Step struct {
-	Action      string    `json:"action"`
-	Description string    `json:"description"`
-	NodeID      int       `json:"nodeId"`
-	ParentID    int       `json:"parentId,omitempty"`
-	Tree        *TreeNode `json:"tree"`
+	Action         string    `json:"action"`
+	Description    string    `json:"description"`
+	NodeID         int       `json:"nodeId"`
+	ParentID       int       `json:"parentId,omitempty"`
+	Tree           *TreeNode `json:"tree"`
+	Stack          []string  `json:"stack,omitempty"`
+	RemainingInput string    `json:"remainingInput,omitempty"`
+	Production     []string  `json:"production,omitempty"`
 }
 
 // ParseResult represents the result of parsing
 type ParseResult struct {
-	Success bool       `json:"success"`
-	Tree    *TreeNode  `json:"tree"`
-	Steps   []Step     `json:"steps"`
-	Error   string     `json:"error,omitempty"`
-	Tokens  []Token    `json:"tokens"`
+	Success   bool          `json:"success"`
+	Tree      *TreeNode     `json:"tree"`
+	Steps     []Step        `json:"steps"`
+	Error     string        `json:"error,omitempty"` // kept for compatibility; holds Errors[0].Message when Errors is non-empty
+	Errors    []ParseError  `json:"errors,omitempty"`
+	Tokens    []Token       `json:"tokens"`
+	Conflicts []Conflict    `json:"conflicts,omitempty"`
+	Automaton *LR0Automaton `json:"automaton,omitempty"` // populated by ParseSLR1
 }
 
 // Production represents a single grammar production
 type Production struct {
-	Head string     `json:"head"`
-	Body [][]string `json:"body"` // Each alternative is a slice of symbols
+	Head         string     `json:"head"`
+	Body         [][]string `json:"body"`                   // Each alternative is a slice of symbols
+	OriginalForm string     `json:"originalForm,omitempty"` // sugared EBNF form, captured before Grammar.Desugar() lowers it
 }
 
 // Grammar represents a context-free grammar
 type Grammar struct {
-	Productions map[string]*Production `json:"productions"`
-	StartSymbol string                 `json:"startSymbol"`
-	Terminals   map[string]bool        `json:"terminals"`
-	NonTerminals map[string]bool       `json:"nonTerminals"`
+	Productions    map[string]*Production `json:"productions"`
+	StartSymbol    string                 `json:"startSymbol"`
+	Terminals      map[string]bool        `json:"terminals"`
+	NonTerminals   map[string]bool        `json:"nonTerminals"`
+	Order          []string               `json:"order,omitempty"`          // non-terminal heads in first-seen order
+	TransformSteps []TransformStep        `json:"transformSteps,omitempty"` // rewrites applied by EliminateLeftRecursion/LeftFactor
 }
 
 // ValidationResult represents the result of grammar validation
 type ValidationResult struct {
-	Valid    bool     `json:"valid"`
-	Errors   []string `json:"errors"`
-	Warnings []string `json:"warnings"`
+	Valid      bool                       `json:"valid"`
+	Errors     []string                   `json:"errors"`
+	Warnings   []string                   `json:"warnings"`
+	FirstSets  map[string]map[string]bool `json:"firstSets,omitempty"`
+	FollowSets map[string]map[string]bool `json:"followSets,omitempty"`
+	Conflicts  []Conflict                 `json:"conflicts,omitempty"`
 }